@@ -0,0 +1,3 @@
+module github.com/colossiai/public-tools/golang/api/pagination
+
+go 1.22
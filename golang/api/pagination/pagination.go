@@ -0,0 +1,173 @@
+// Package pagination walks paged list endpoints. The caller supplies a
+// FetchFunc that returns one page and the cursor of the next; Pages and
+// Iterator drive it until the last page, so list wrappers don't each carry
+// their own loop.
+//
+//	it := pagination.NewIterator(func(ctx context.Context, cursor string) (pagination.Page[Order], error) {
+//		var resp ListOrdersResp
+//		if err := client.ListOrders(ctx, cursor, &resp); err != nil {
+//			return pagination.Page[Order]{}, err
+//		}
+//		return pagination.Page[Order]{Items: resp.Orders, Next: resp.NextCursor}, nil
+//	})
+//	for it.Next(ctx) {
+//		process(it.Item())
+//	}
+//	if err := it.Err(); err != nil { ... }
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Page is one page of results.
+type Page[T any] struct {
+	Items []T
+	Next  string // cursor of the following page; empty on the last page
+}
+
+// FetchFunc fetches the page at cursor. The first call gets an empty cursor.
+type FetchFunc[T any] func(ctx context.Context, cursor string) (Page[T], error)
+
+// Offset adapts an offset/limit endpoint to a FetchFunc. The offset is carried
+// in the cursor, and a page shorter than limit is taken as the last one.
+func Offset[T any](limit int, fetch func(ctx context.Context, offset, limit int) ([]T, error)) FetchFunc[T] {
+	return func(ctx context.Context, cursor string) (Page[T], error) {
+		offset := 0
+		if cursor != "" {
+			var err error
+			if offset, err = strconv.Atoi(cursor); err != nil {
+				return Page[T]{}, fmt.Errorf("pagination: bad offset cursor %q: %w", cursor, err)
+			}
+		}
+		items, err := fetch(ctx, offset, limit)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		page := Page[T]{Items: items}
+		if len(items) >= limit && len(items) > 0 {
+			page.Next = strconv.Itoa(offset + len(items))
+		}
+		return page, nil
+	}
+}
+
+// Pages iterates over whole pages.
+type Pages[T any] struct {
+	fetch   FetchFunc[T]
+	cursor  string
+	started bool
+	done    bool
+	page    []T
+	err     error
+}
+
+// NewPages returns a Pages that starts at the first page.
+func NewPages[T any](fetch FetchFunc[T]) *Pages[T] {
+	return &Pages[T]{fetch: fetch}
+}
+
+// Next fetches the next page and reports whether there is one. It returns
+// false after the last page, on a fetch error, or once ctx is done; check Err
+// to tell these apart.
+func (p *Pages[T]) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	if p.started && p.cursor == "" {
+		p.done = true
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		p.fail(err)
+		return false
+	}
+	page, err := p.fetch(ctx, p.cursor)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if page.Next != "" && page.Next == p.cursor {
+		p.fail(fmt.Errorf("pagination: cursor %q did not advance", p.cursor))
+		return false
+	}
+	p.started = true
+	p.cursor = page.Next
+	p.page = page.Items
+	return true
+}
+
+func (p *Pages[T]) fail(err error) {
+	p.err = err
+	p.done = true
+	p.page = nil
+}
+
+// Page returns the page fetched by the last successful Next.
+func (p *Pages[T]) Page() []T { return p.page }
+
+// Err returns the error that stopped iteration, if any.
+func (p *Pages[T]) Err() error { return p.err }
+
+// Iterator iterates over individual items across pages.
+type Iterator[T any] struct {
+	pages *Pages[T]
+	buf   []T
+	item  T
+}
+
+// NewIterator returns an Iterator that starts at the first item.
+func NewIterator[T any](fetch FetchFunc[T]) *Iterator[T] {
+	return &Iterator[T]{pages: NewPages(fetch)}
+}
+
+// Next advances to the next item, fetching pages as needed. Empty pages are
+// skipped.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for len(it.buf) == 0 {
+		if !it.pages.Next(ctx) {
+			return false
+		}
+		it.buf = it.pages.Page()
+	}
+	if err := ctx.Err(); err != nil {
+		it.pages.fail(err)
+		it.buf = nil
+		return false
+	}
+	it.item, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Item returns the current item.
+func (it *Iterator[T]) Item() T { return it.item }
+
+// Err returns the error that stopped iteration, if any.
+func (it *Iterator[T]) Err() error { return it.pages.Err() }
+
+// All streams the remaining items over the first channel, which is closed when
+// iteration ends. The error channel then yields the iteration error, if any,
+// and is closed. A consumer that stops reading early must cancel ctx so the
+// producing goroutine can exit.
+func (it *Iterator[T]) All(ctx context.Context) (<-chan T, <-chan error) {
+	items := make(chan T)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(items)
+		for it.Next(ctx) {
+			select {
+			case items <- it.Item():
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			errc <- err
+		}
+	}()
+	return items, errc
+}
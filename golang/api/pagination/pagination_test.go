@@ -0,0 +1,157 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// cursorPages serves pages keyed by cursor; "" is the first page.
+func cursorPages(pages map[string]Page[int]) FetchFunc[int] {
+	return func(_ context.Context, cursor string) (Page[int], error) {
+		p, ok := pages[cursor]
+		if !ok {
+			return Page[int]{}, errors.New("unknown cursor " + strconv.Quote(cursor))
+		}
+		return p, nil
+	}
+}
+
+func collect(t *testing.T, it *Iterator[int]) []int {
+	t.Helper()
+	var got []int
+	for it.Next(context.Background()) {
+		got = append(got, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestIteratorCursor(t *testing.T) {
+	it := NewIterator(cursorPages(map[string]Page[int]{
+		"":  {Items: []int{1, 2}, Next: "b"},
+		"b": {Next: "c"}, // empty pages are skipped
+		"c": {Items: []int{3}},
+	}))
+	if got, want := collect(t, it), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPages(t *testing.T) {
+	p := NewPages(cursorPages(map[string]Page[int]{
+		"":  {Items: []int{1, 2}, Next: "b"},
+		"b": {Items: []int{3}},
+	}))
+	var got [][]int
+	for p.Next(context.Background()) {
+		got = append(got, p.Page())
+	}
+	if err := p.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]int{{1, 2}, {3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestOffset(t *testing.T) {
+	data := []int{0, 1, 2, 3, 4}
+	var calls int
+	it := NewIterator(Offset(2, func(_ context.Context, offset, limit int) ([]int, error) {
+		calls++
+		end := min(offset+limit, len(data))
+		return data[offset:end], nil
+	}))
+	if got := collect(t, it); !reflect.DeepEqual(got, data) {
+		t.Errorf("got %v, want %v", got, data)
+	}
+	if calls != 3 {
+		t.Errorf("fetched %d pages, want 3", calls)
+	}
+}
+
+func TestFetchError(t *testing.T) {
+	boom := errors.New("boom")
+	it := NewIterator(func(_ context.Context, cursor string) (Page[int], error) {
+		if cursor == "" {
+			return Page[int]{Items: []int{1}, Next: "b"}, nil
+		}
+		return Page[int]{}, boom
+	})
+	ctx := context.Background()
+	if !it.Next(ctx) || it.Item() != 1 {
+		t.Fatal("expected first item")
+	}
+	if it.Next(ctx) {
+		t.Fatal("expected iteration to stop")
+	}
+	if !errors.Is(it.Err(), boom) {
+		t.Errorf("Err() = %v, want %v", it.Err(), boom)
+	}
+}
+
+func TestCursorMustAdvance(t *testing.T) {
+	it := NewIterator(func(context.Context, string) (Page[int], error) {
+		return Page[int]{Next: "same"}, nil
+	})
+	for it.Next(context.Background()) {
+	}
+	if it.Err() == nil {
+		t.Error("expected error for repeated cursor")
+	}
+}
+
+func TestCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	it := NewIterator(func(_ context.Context, cursor string) (Page[int], error) {
+		return Page[int]{Items: []int{1, 2}, Next: cursor + "x"}, nil
+	})
+	if !it.Next(ctx) {
+		t.Fatal("expected first item")
+	}
+	cancel()
+	if it.Next(ctx) {
+		t.Fatal("Next succeeded after cancel")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", it.Err())
+	}
+}
+
+func TestAll(t *testing.T) {
+	it := NewIterator(cursorPages(map[string]Page[int]{
+		"":  {Items: []int{1, 2}, Next: "b"},
+		"b": {Items: []int{3}},
+	}))
+	items, errc := it.All(context.Background())
+	var got []int
+	for v := range items {
+		got = append(got, v)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestAllCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	it := NewIterator(func(_ context.Context, cursor string) (Page[int], error) {
+		return Page[int]{Items: []int{1}, Next: cursor + "x"}, nil
+	})
+	items, errc := it.All(ctx)
+	<-items
+	cancel()
+	for range items {
+	}
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/colossiai/public-tools/monitoring/grafana-dashboard
+
+go 1.22
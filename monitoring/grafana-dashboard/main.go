@@ -0,0 +1,271 @@
+// Command grafana-dashboard prints a ready-to-import Grafana dashboard for the
+// server metrics exported by grpc_prometheus (grpc_server_started_total,
+// grpc_server_handled_total, grpc_server_handling_seconds_bucket and
+// grpc_server_msg_{received,sent}_total).
+//
+//	go run . -job grafana-grpc > dashboard.json
+//
+// The latency panels query grpc_server_handling_seconds_bucket, which is only
+// exported once handling-time histograms are enabled on the server.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+)
+
+type config struct {
+	Job       string // Prometheus job label the server is scraped under
+	Namespace string // metric namespace; empty for the grpc_prometheus defaults
+	Subsystem string // metric subsystem, placed between namespace and name
+	Title     string
+	UID       string // optional, lets re-imports overwrite the same dashboard
+}
+
+type dashboard struct {
+	UID           string     `json:"uid,omitempty"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	Timezone      string     `json:"timezone"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          timeRange  `json:"time"`
+	Templating    templating `json:"templating"`
+	Panels        []panel    `json:"panels"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type templating struct {
+	List []variable `json:"list"`
+}
+
+type variable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+type panel struct {
+	ID          int         `json:"id"`
+	Type        string      `json:"type"`
+	Title       string      `json:"title"`
+	Description string      `json:"description,omitempty"`
+	GridPos     gridPos     `json:"gridPos"`
+	Datasource  datasource  `json:"datasource"`
+	FieldConfig fieldConfig `json:"fieldConfig"`
+	Targets     []target    `json:"targets"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type fieldConfig struct {
+	Defaults fieldDefaults `json:"defaults"`
+}
+
+type fieldDefaults struct {
+	Unit string `json:"unit"`
+}
+
+type target struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+}
+
+// panelSpec is the part of a panel that differs between panels; layout fills
+// in the rest.
+type panelSpec struct {
+	title       string
+	description string
+	unit        string
+	targets     []target
+}
+
+const latencyNote = "Requires handling-time histograms to be enabled on the server."
+
+// metric joins the non-empty namespace, subsystem and name with underscores,
+// the same way prometheus.BuildFQName names the server's collectors.
+func (c config) metric(name string) string {
+	for _, prefix := range []string{c.Subsystem, c.Namespace} {
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+	}
+	return name
+}
+
+// rate returns the per-second rate of metric for the configured job, summed
+// by the given labels.
+func (c config) rate(metric, matchers, by string) string {
+	sel := "job=" + strconv.Quote(c.Job)
+	if matchers != "" {
+		sel += "," + matchers
+	}
+	return fmt.Sprintf("sum by (%s) (rate(%s{%s}[$__rate_interval]))", by, c.metric(metric), sel)
+}
+
+func (c config) errorRatio(by string) string {
+	return c.rate("grpc_server_handled_total", `grpc_code!="OK"`, by) +
+		" / " + c.rate("grpc_server_handled_total", "", by)
+}
+
+func (c config) quantile(q, by string) string {
+	return fmt.Sprintf("histogram_quantile(%s, %s)", q, c.rate("grpc_server_handling_seconds_bucket", "", by))
+}
+
+func buildDashboard(c config) dashboard {
+	const byMethod = "grpc_service, grpc_method"
+	const methodLegend = "{{grpc_service}}/{{grpc_method}}"
+
+	specs := []panelSpec{
+		{
+			title:   "Requests per second",
+			unit:    "reqps",
+			targets: []target{{Expr: c.rate("grpc_server_started_total", "", "job"), LegendFormat: "started"}},
+		},
+		{
+			title:   "Error rate",
+			unit:    "percentunit",
+			targets: []target{{Expr: c.errorRatio("job"), LegendFormat: "non-OK"}},
+		},
+		{
+			title:       "Latency quantiles",
+			description: latencyNote,
+			unit:        "s",
+			targets: []target{
+				{Expr: c.quantile("0.5", "le"), LegendFormat: "p50"},
+				{Expr: c.quantile("0.9", "le"), LegendFormat: "p90"},
+				{Expr: c.quantile("0.99", "le"), LegendFormat: "p99"},
+			},
+		},
+		{
+			title: "Messages per second",
+			unit:  "ops",
+			targets: []target{
+				{Expr: c.rate("grpc_server_msg_received_total", "", "job"), LegendFormat: "received"},
+				{Expr: c.rate("grpc_server_msg_sent_total", "", "job"), LegendFormat: "sent"},
+			},
+		},
+		{
+			title:   "Requests per second by method",
+			unit:    "reqps",
+			targets: []target{{Expr: c.rate("grpc_server_handled_total", "", byMethod), LegendFormat: methodLegend}},
+		},
+		{
+			title:   "Error rate by method",
+			unit:    "percentunit",
+			targets: []target{{Expr: c.errorRatio(byMethod), LegendFormat: methodLegend}},
+		},
+		{
+			title:       "p99 latency by method",
+			description: latencyNote,
+			unit:        "s",
+			targets:     []target{{Expr: c.quantile("0.99", "le, "+byMethod), LegendFormat: methodLegend}},
+		},
+	}
+
+	return dashboard{
+		UID:           c.UID,
+		Title:         c.Title,
+		Tags:          []string{"grpc", "prometheus"},
+		Timezone:      "browser",
+		SchemaVersion: 39,
+		Refresh:       "30s",
+		Time:          timeRange{From: "now-1h", To: "now"},
+		Templating: templating{List: []variable{
+			{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+		}},
+		Panels: layout(specs),
+	}
+}
+
+// layout places panels two per row, each half the dashboard width.
+func layout(specs []panelSpec) []panel {
+	const w, h = 12, 8
+	panels := make([]panel, len(specs))
+	for i, s := range specs {
+		for j := range s.targets {
+			s.targets[j].RefID = string(rune('A' + j))
+		}
+		panels[i] = panel{
+			ID:          i + 1,
+			Type:        "timeseries",
+			Title:       s.title,
+			Description: s.description,
+			GridPos:     gridPos{H: h, W: w, X: (i % 2) * w, Y: (i / 2) * h},
+			Datasource:  datasource{Type: "prometheus", UID: "${datasource}"},
+			FieldConfig: fieldConfig{Defaults: fieldDefaults{Unit: s.unit}},
+			Targets:     s.targets,
+		}
+	}
+	return panels
+}
+
+func writeDashboard(w io.Writer, c config) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(buildDashboard(c))
+}
+
+// writeFile writes the dashboard to path. A file that could not be written
+// in full is removed rather than left half-encoded.
+func writeFile(path string, c config) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = writeDashboard(f, c)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
+
+func main() {
+	var c config
+	flag.StringVar(&c.Job, "job", "grafana-grpc", "Prometheus job label of the gRPC server")
+	flag.StringVar(&c.Namespace, "namespace", "", "metric namespace configured on the server, if any")
+	flag.StringVar(&c.Subsystem, "subsystem", "", "metric subsystem configured on the server, if any")
+	flag.StringVar(&c.Title, "title", "gRPC Server", "dashboard title")
+	flag.StringVar(&c.UID, "uid", "", "dashboard UID (optional)")
+	out := flag.String("out", "", "output file (default stdout)")
+	flag.Parse()
+
+	if c.Job == "" {
+		log.Fatal("-job must not be empty")
+	}
+
+	var err error
+	if *out == "" {
+		err = writeDashboard(os.Stdout, c)
+	} else {
+		err = writeFile(*out, c)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
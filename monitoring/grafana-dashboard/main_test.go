@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestDashboardGolden(t *testing.T) {
+	var buf bytes.Buffer
+	c := config{Job: "grafana-grpc", Title: "gRPC Server"}
+	if err := writeDashboard(&buf, c); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "dashboard.json")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("dashboard differs from %s; rerun with -update if the change is intended\n%s", golden, buf.String())
+	}
+}
+
+func TestNamespacePrefixesMetrics(t *testing.T) {
+	tests := []struct {
+		namespace, subsystem, prefix string
+	}{
+		{"acme", "", "acme_grpc_server_"},
+		{"", "demo", "demo_grpc_server_"},
+		{"acme", "demo", "acme_demo_grpc_server_"},
+	}
+	for _, tt := range tests {
+		d := buildDashboard(config{Job: "demo", Namespace: tt.namespace, Subsystem: tt.subsystem})
+		for _, p := range d.Panels {
+			for _, tg := range p.Targets {
+				if !strings.Contains(tg.Expr, tt.prefix) {
+					t.Errorf("namespace %q, subsystem %q, panel %q: expr %q lacks prefix %q",
+						tt.namespace, tt.subsystem, p.Title, tg.Expr, tt.prefix)
+				}
+				if !strings.Contains(tg.Expr, `job="demo"`) {
+					t.Errorf("panel %q: expr %q lacks job selector", p.Title, tg.Expr)
+				}
+			}
+		}
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	c := config{Job: "grafana-grpc", Title: "gRPC Server"}
+	path := filepath.Join(t.TempDir(), "dashboard.json")
+	if err := writeFile(path, c); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want bytes.Buffer
+	if err := writeDashboard(&want, c); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Error("file contents differ from writeDashboard output")
+	}
+}